@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bscott/drone/pkg/model"
+)
+
+// StreamRepos handles GET /api/stream/repos, streaming every repo
+// lifecycle event to the client as they are published on the firehose.
+// Internal consumers (webhook dispatcher, metrics, audit log) can
+// subscribe to model.Events directly instead; this handler exposes the
+// same bus to authorized external clients over SSE.
+func StreamRepos(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	events := model.Events.Subscribe(0)
+	defer model.Events.Unsubscribe(0, events)
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bscott/drone/pkg/model"
+)
+
+// RepoByIDStore loads a repo by its ID, used by KeyHandler to build the
+// *model.Repo that KeyService needs in order to label the events it
+// publishes.
+type RepoByIDStore interface {
+	RepoByID(id int64) (*model.Repo, error)
+}
+
+// KeyHandler exposes HTTP endpoints for managing repository deploy keys.
+type KeyHandler struct {
+	Repos RepoByIDStore
+	Keys  model.KeyService
+}
+
+// RotateKey handles PATCH /api/repos/:id/key, regenerating the deploy
+// key for a repository without requiring the repo to be deactivated and
+// reactivated.
+func (h *KeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	repoID, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repo, err := h.Repos.RepoByID(repoID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	key, err := h.Keys.Rotate(repo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
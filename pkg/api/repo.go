@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bscott/drone/pkg/model"
+)
+
+// RepoStore loads and persists repos for the repo-editing handlers.
+type RepoStore interface {
+	RepoByOwnerName(owner, name string) (*model.Repo, error)
+	UpdateRepo(*model.Repo) error
+}
+
+// RepoHandler exposes HTTP endpoints for editing repository settings.
+type RepoHandler struct {
+	Repos RepoStore
+}
+
+// patchRepoInput is the set of fields PatchRepo accepts. Pointer fields
+// distinguish "not provided" from "provided, set to the zero value".
+//
+// config_path overrides the default .drone.yml lookup path, but only as
+// a fallback: ResolveConfig still checks .drone.yml first and only
+// consults config_path when .drone.yml is absent from the repo. See
+// model.Repo.ResolveConfig.
+//
+// visibility controls who may see the repo on this CI server (public,
+// private or internal) and is consulted by auth middleware and the API
+// layer in place of the legacy private flag; see model.Repo.CanView.
+type patchRepoInput struct {
+	ConfigPath *string `json:"config_path"`
+	Visibility *string `json:"visibility"`
+}
+
+// PatchRepo handles PATCH /api/repos/:owner/:name, applying partial
+// updates to repo settings: config_path and visibility.
+func (h *RepoHandler) PatchRepo(w http.ResponseWriter, r *http.Request, owner, name string) {
+	repo, err := h.Repos.RepoByOwnerName(owner, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var in patchRepoInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if in.ConfigPath != nil {
+		repo.ConfigPath = *in.ConfigPath
+	}
+	if in.Visibility != nil {
+		if err := repo.SetVisibility(model.RepoVisibility(*in.Visibility)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.Repos.UpdateRepo(repo); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(repo)
+}
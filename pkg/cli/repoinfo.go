@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bscott/drone/pkg/model"
+)
+
+// WriteRepoInfo writes the human-readable "repo info" output for repo to
+// w, as used by the `drone repo info` command.
+func WriteRepoInfo(w io.Writer, repo *model.Repo) {
+	fmt.Fprintf(w, "Slug: %s\n", repo.Slug)
+	fmt.Fprintf(w, "Visibility: %s\n", repo.Visibility())
+	fmt.Fprintf(w, "Config Path: %s\n", repoConfigPath(repo))
+	fmt.Fprintf(w, "Timeout: %d\n", repo.Timeout)
+}
+
+// repoConfigPath returns the effective config path for display. It
+// spells out the default rather than printing an empty string, and
+// notes that a custom path is only a fallback used when
+// model.DefaultConfigPath is absent from the repo; see
+// model.Repo.ResolveConfig.
+func repoConfigPath(repo *model.Repo) string {
+	if repo.ConfigPath == "" {
+		return model.DefaultConfigPath + " (default)"
+	}
+	return fmt.Sprintf("%s (fallback, used only if %s is absent)", repo.ConfigPath, model.DefaultConfigPath)
+}
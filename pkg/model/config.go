@@ -0,0 +1,13 @@
+package model
+
+// KeyAlgorithmFromFlag parses the --key-algorithm server config flag into
+// a KeyAlgorithm, defaulting to RSA for backwards compatibility with
+// existing deployments when the flag is unset or unrecognized.
+func KeyAlgorithmFromFlag(flag string) KeyAlgorithm {
+	switch KeyAlgorithm(flag) {
+	case KeyAlgorithmEd25519:
+		return KeyAlgorithmEd25519
+	default:
+		return KeyAlgorithmRSA
+	}
+}
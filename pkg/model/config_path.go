@@ -0,0 +1,36 @@
+package model
+
+import "context"
+
+// DefaultConfigPath is the build config path used when a repo has no
+// ConfigPath of its own.
+const DefaultConfigPath = ".drone.yml"
+
+// ConfigFetcher retrieves the raw contents of a file at path within a
+// repository, as used by Repo.ResolveConfig to locate the build config.
+// Fetch must return an error for any failure, including "file does not
+// exist" — ConfigFetcher has no way to distinguish that from a transient
+// or network error, so ResolveConfig treats any error the same way: as
+// "not present at this path, try the next one."
+type ConfigFetcher interface {
+	Fetch(ctx context.Context, path string) ([]byte, error)
+}
+
+// ResolveConfig returns the build config for the repo. DefaultConfigPath
+// (.drone.yml) always takes precedence when present, and r.ConfigPath is
+// used only as a fallback when it is absent. This keeps rollout of a
+// custom path (for example ci/drone.yml) incremental: setting ConfigPath
+// on a repo that still has a root .drone.yml has no effect until that
+// file is removed, rather than flipping resolution over immediately.
+// Because ConfigFetcher can't distinguish "not found" from a transient
+// fetch error, any error fetching .drone.yml — not just a missing file —
+// triggers the fallback to ConfigPath.
+func (r *Repo) ResolveConfig(ctx context.Context, fetcher ConfigFetcher) ([]byte, error) {
+	if data, err := fetcher.Fetch(ctx, DefaultConfigPath); err == nil {
+		return data, nil
+	}
+	if r.ConfigPath != "" && r.ConfigPath != DefaultConfigPath {
+		return fetcher.Fetch(ctx, r.ConfigPath)
+	}
+	return fetcher.Fetch(ctx, DefaultConfigPath)
+}
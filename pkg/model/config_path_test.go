@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeFetcher serves fixed content for a set of paths and returns
+// errNotFound for everything else.
+type fakeFetcher map[string][]byte
+
+var errNotFound = errors.New("not found")
+
+func (f fakeFetcher) Fetch(ctx context.Context, path string) ([]byte, error) {
+	data, ok := f[path]
+	if !ok {
+		return nil, errNotFound
+	}
+	return data, nil
+}
+
+func TestResolveConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+		fetcher    fakeFetcher
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "drone.yml present, no custom path",
+			configPath: "",
+			fetcher:    fakeFetcher{".drone.yml": []byte("root")},
+			want:       "root",
+		},
+		{
+			name:       "drone.yml present takes precedence over custom path",
+			configPath: "ci/drone.yml",
+			fetcher:    fakeFetcher{".drone.yml": []byte("root"), "ci/drone.yml": []byte("custom")},
+			want:       "root",
+		},
+		{
+			name:       "drone.yml absent falls back to custom path",
+			configPath: "ci/drone.yml",
+			fetcher:    fakeFetcher{"ci/drone.yml": []byte("custom")},
+			want:       "custom",
+		},
+		{
+			name:       "drone.yml and custom path both absent",
+			configPath: "ci/drone.yml",
+			fetcher:    fakeFetcher{},
+			wantErr:    true,
+		},
+		{
+			name:       "no custom path and drone.yml absent",
+			configPath: "",
+			fetcher:    fakeFetcher{},
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Repo{ConfigPath: test.configPath}
+			data, err := r.ResolveConfig(context.Background(), test.fetcher)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("ResolveConfig() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveConfig() returned error: %v", err)
+			}
+			if string(data) != test.want {
+				t.Errorf("ResolveConfig() = %q, want %q", data, test.want)
+			}
+		})
+	}
+}
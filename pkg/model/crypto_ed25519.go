@@ -0,0 +1,49 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+)
+
+// generateEd25519Key generates a new Ed25519 key pair for a deploy key.
+func generateEd25519Key() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+// marshalEd25519PublicKey renders pub in OpenSSH authorized_keys format
+// (RFC 4253 section 6.6 wire encoding, base64-encoded and prefixed with
+// the key type), the form sshd expects in .ssh/authorized_keys.
+func marshalEd25519PublicKey(pub ed25519.PublicKey) string {
+	const keyType = "ssh-ed25519"
+
+	buf := sshString([]byte(keyType))
+	buf = append(buf, sshString(pub)...)
+
+	return keyType + " " + base64.StdEncoding.EncodeToString(buf)
+}
+
+// marshalEd25519PrivateKey renders priv as a PEM-encoded PKCS#8 private
+// key, the form written to .ssh/id_ed25519 in the build environment.
+func marshalEd25519PrivateKey(priv ed25519.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		// ed25519.PrivateKey always marshals successfully; this would
+		// only fail if the standard library's encoding changed.
+		panic(err)
+	}
+	block := &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	return string(pem.EncodeToMemory(block))
+}
+
+// sshString encodes b as an SSH wire-format string: a 4-byte big-endian
+// length prefix followed by the raw bytes.
+func sshString(b []byte) []byte {
+	out := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(out, uint32(len(b)))
+	copy(out[4:], b)
+	return out
+}
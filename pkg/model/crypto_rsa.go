@@ -0,0 +1,50 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+)
+
+// rsaKeyBits is the modulus size used for generated RSA deploy keys.
+const rsaKeyBits = 2048
+
+// generatePrivateKey generates a new RSA private key for a deploy key.
+func generatePrivateKey() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+}
+
+// marshalPublicKey renders pub in OpenSSH authorized_keys format (RFC
+// 4253 section 6.6 wire encoding, base64-encoded and prefixed with the
+// key type), the form sshd expects in .ssh/authorized_keys.
+func marshalPublicKey(pub *rsa.PublicKey) string {
+	const keyType = "ssh-rsa"
+
+	buf := sshString([]byte(keyType))
+	buf = append(buf, sshMPInt(big.NewInt(int64(pub.E)))...)
+	buf = append(buf, sshMPInt(pub.N)...)
+
+	return keyType + " " + base64.StdEncoding.EncodeToString(buf)
+}
+
+// marshalPrivateKey renders priv as a PEM-encoded PKCS#1 private key,
+// the form written to .ssh/id_rsa in the build environment.
+func marshalPrivateKey(priv *rsa.PrivateKey) string {
+	der := x509.MarshalPKCS1PrivateKey(priv)
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}
+	return string(pem.EncodeToMemory(block))
+}
+
+// sshMPInt encodes n as an SSH wire-format mpint: sshString of its
+// big-endian bytes, with a leading zero byte inserted when the
+// high-order bit is set so the value is never mistaken for negative.
+func sshMPInt(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return sshString(b)
+}
@@ -0,0 +1,153 @@
+package model
+
+import "strconv"
+
+// EventKind identifies the lifecycle event emitted for a repo.
+type EventKind string
+
+const (
+	EventRepoCreated           EventKind = "repo.created"
+	EventRepoEnabled           EventKind = "repo.enabled"
+	EventRepoDisabled          EventKind = "repo.disabled"
+	EventRepoKeyActivated      EventKind = "repo.key_activated"
+	EventRepoKeyRotated        EventKind = "repo.key_rotated"
+	EventRepoParamsChanged     EventKind = "repo.params_changed"
+	EventRepoVisibilityChanged EventKind = "repo.visibility_changed"
+	EventRepoDeleted           EventKind = "repo.deleted"
+)
+
+// RepoEvent is a structured message describing a single repo lifecycle
+// change. Labels carry filterable metadata so subscribers can select the
+// events they care about without inspecting the Repo itself.
+type RepoEvent struct {
+	Kind   EventKind         `json:"kind"`
+	RepoID int64             `json:"repo_id"`
+	Labels map[string]string `json:"labels"`
+}
+
+// repoLabels builds the standard label set attached to every RepoEvent.
+func repoLabels(r *Repo) map[string]string {
+	return map[string]string{
+		"repo":    r.Slug,
+		"private": strconv.FormatBool(r.IsSCMPrivate),
+		"owner":   r.Owner,
+		"host":    r.Host,
+	}
+}
+
+// Events is the process-wide repo event bus. Repo lifecycle methods
+// publish to it so the growing list of side effects (webhook dispatch,
+// metrics, audit logging, ...) can subscribe instead of being called
+// directly from the handlers that mutate repos.
+var Events = NewRepoEvents()
+
+// RepoEvents is a pub/sub bus for repo lifecycle events. Publishing
+// fans out to subscribers of the affected repo's topic as well as to
+// the global firehose.
+type RepoEvents struct {
+	subs chan subscription
+	pub  chan RepoEvent
+	done chan struct{}
+
+	topics   map[int64][]chan RepoEvent
+	firehose []chan RepoEvent
+	unsub    chan unsubscription
+}
+
+type subscription struct {
+	repoID int64
+	ch     chan RepoEvent
+}
+
+type unsubscription struct {
+	repoID int64
+	ch     chan RepoEvent
+}
+
+// NewRepoEvents creates an empty RepoEvents bus and starts its dispatch
+// loop.
+func NewRepoEvents() *RepoEvents {
+	b := &RepoEvents{
+		subs:   make(chan subscription),
+		pub:    make(chan RepoEvent),
+		unsub:  make(chan unsubscription),
+		done:   make(chan struct{}),
+		topics: map[int64][]chan RepoEvent{},
+	}
+	go b.loop()
+	return b
+}
+
+func (b *RepoEvents) loop() {
+	for {
+		select {
+		case s := <-b.subs:
+			if s.repoID == 0 {
+				b.firehose = append(b.firehose, s.ch)
+			} else {
+				b.topics[s.repoID] = append(b.topics[s.repoID], s.ch)
+			}
+		case u := <-b.unsub:
+			if u.repoID == 0 {
+				b.firehose = removeSub(b.firehose, u.ch)
+			} else {
+				subs := removeSub(b.topics[u.repoID], u.ch)
+				if len(subs) == 0 {
+					delete(b.topics, u.repoID)
+				} else {
+					b.topics[u.repoID] = subs
+				}
+			}
+			close(u.ch)
+		case event := <-b.pub:
+			for _, ch := range b.topics[event.RepoID] {
+				trySend(ch, event)
+			}
+			for _, ch := range b.firehose {
+				trySend(ch, event)
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Subscribe returns a channel that receives every event published for
+// repoID. A repoID of 0 subscribes to the firehose: every event for
+// every repo. Call Unsubscribe with the same repoID and channel when
+// done to release it.
+func (b *RepoEvents) Subscribe(repoID int64) chan RepoEvent {
+	ch := make(chan RepoEvent, 16)
+	b.subs <- subscription{repoID, ch}
+	return ch
+}
+
+// Unsubscribe stops delivering events to ch and closes it. ch must be a
+// channel previously returned by Subscribe with the same repoID.
+func (b *RepoEvents) Unsubscribe(repoID int64, ch chan RepoEvent) {
+	b.unsub <- unsubscription{repoID, ch}
+}
+
+// Publish emits event to every subscriber of the repo's topic and to
+// the firehose. Publish never blocks on a slow subscriber; events are
+// dropped for subscribers whose buffer is full.
+func (b *RepoEvents) Publish(event RepoEvent) {
+	b.pub <- event
+}
+
+func trySend(ch chan RepoEvent, event RepoEvent) {
+	select {
+	case ch <- event:
+	default:
+	}
+}
+
+func removeSub(subs []chan RepoEvent, target chan RepoEvent) []chan RepoEvent {
+	out := subs[:0]
+	for _, ch := range subs {
+		if ch != target {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
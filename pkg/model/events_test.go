@@ -0,0 +1,92 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testEventTimeout = time.Second
+
+func TestRepoEventsFanOut(t *testing.T) {
+	bus := NewRepoEvents()
+
+	topicA := bus.Subscribe(1)
+	defer bus.Unsubscribe(1, topicA)
+	topicB := bus.Subscribe(2)
+	defer bus.Unsubscribe(2, topicB)
+	firehose := bus.Subscribe(0)
+	defer bus.Unsubscribe(0, firehose)
+
+	event := RepoEvent{Kind: EventRepoCreated, RepoID: 1}
+	bus.Publish(event)
+
+	select {
+	case got := <-topicA:
+		if !reflect.DeepEqual(got, event) {
+			t.Errorf("topic 1 received %+v, want %+v", got, event)
+		}
+	case <-time.After(testEventTimeout):
+		t.Error("topic 1 subscriber did not receive the event")
+	}
+
+	select {
+	case got := <-firehose:
+		if !reflect.DeepEqual(got, event) {
+			t.Errorf("firehose received %+v, want %+v", got, event)
+		}
+	case <-time.After(testEventTimeout):
+		t.Error("firehose subscriber did not receive the event")
+	}
+
+	select {
+	case got := <-topicB:
+		t.Errorf("topic 2 subscriber should not receive an event for repo 1, got %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRepoEventsDropsWhenBufferFull(t *testing.T) {
+	bus := NewRepoEvents()
+
+	sub := bus.Subscribe(1)
+	defer bus.Unsubscribe(1, sub)
+
+	// The subscriber channel is buffered at 16 and nothing is draining
+	// it, so publishing well past that should not block the publisher
+	// and should silently drop events once the buffer is full.
+	for i := 0; i < 64; i++ {
+		bus.Publish(RepoEvent{Kind: EventRepoCreated, RepoID: 1})
+	}
+
+	count := 0
+drain:
+	for {
+		select {
+		case <-sub:
+			count++
+		case <-time.After(200 * time.Millisecond):
+			break drain
+		}
+	}
+
+	if count != 16 {
+		t.Errorf("subscriber buffered %d events, want 16 (the channel capacity)", count)
+	}
+}
+
+func TestRepoEventsUnsubscribeClosesChannel(t *testing.T) {
+	bus := NewRepoEvents()
+
+	sub := bus.Subscribe(1)
+	bus.Unsubscribe(1, sub)
+
+	_, ok := <-sub
+	if ok {
+		t.Error("channel should be closed after Unsubscribe")
+	}
+
+	// Publishing after the only subscriber unsubscribed should not panic
+	// or block.
+	bus.Publish(RepoEvent{Kind: EventRepoCreated, RepoID: 1})
+}
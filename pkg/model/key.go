@@ -0,0 +1,132 @@
+package model
+
+import "time"
+
+// KeyAlgorithm identifies the asymmetric algorithm used to generate a
+// repository's deploy key pair.
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA     KeyAlgorithm = "rsa"
+	KeyAlgorithmEd25519 KeyAlgorithm = "ed25519"
+)
+
+// Key is the deploy key pair injected into the build environment so that
+// it can clone the repository over SSH. Keys are stored separately from
+// Repo, keyed by RepoID, so that constructing a Repo never has the side
+// effect of generating key material and a repo's key can be rotated
+// without recreating the repo.
+type Key struct {
+	ID int64 `meddler:"id,pk" json:"id"`
+
+	// RepoID is the foreign key of the Repo this key pair belongs to.
+	RepoID int64 `meddler:"repo_id" json:"repo_id"`
+
+	// Algorithm is the asymmetric algorithm used to generate this key
+	// pair.
+	Algorithm KeyAlgorithm `meddler:"algorithm" json:"algorithm"`
+
+	// Public and Private hold the key material injected into the
+	// virtual machine's .ssh/id_rsa(.pub) or .ssh/id_ed25519(.pub)
+	// files, depending on Algorithm.
+	Public  string `meddler:"public_key"  json:"public_key"`
+	Private string `meddler:"private_key" json:"-"`
+
+	Created time.Time `meddler:"created,utctime" json:"created"`
+	Updated time.Time `meddler:"updated,utctime" json:"updated"`
+}
+
+// NewKey generates a new deploy Key for the repository identified by
+// repoID, using algo. Ed25519 is preferred by modern SSH servers and,
+// unlike RSA, is short enough to fit on a single authorized_keys line.
+func NewKey(repoID int64, algo KeyAlgorithm) (*Key, error) {
+	key := &Key{RepoID: repoID, Algorithm: algo}
+
+	switch algo {
+	case KeyAlgorithmEd25519:
+		pub, priv, err := generateEd25519Key()
+		if err != nil {
+			return nil, err
+		}
+		key.Public = marshalEd25519PublicKey(pub)
+		key.Private = marshalEd25519PrivateKey(priv)
+	default:
+		priv, err := generatePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		key.Algorithm = KeyAlgorithmRSA
+		key.Public = marshalPublicKey(&priv.PublicKey)
+		key.Private = marshalPrivateKey(priv)
+	}
+
+	return key, nil
+}
+
+// KeyStore persists deploy keys. It is implemented by the datastore
+// package; it is abstracted here so that model has no dependency on the
+// database driver.
+type KeyStore interface {
+	CreateKey(*Key) error
+	UpdateKey(*Key) error
+	KeyByRepoID(repoID int64) (*Key, error)
+}
+
+// KeyService generates and rotates deploy keys for repositories. It is
+// invoked at repo activation time, rather than from NewRepo, so that
+// building a Repo value never has the side effect of generating key
+// material. It takes the full *Repo, rather than just a repo ID, so the
+// events it publishes can carry the standard repo labels.
+type KeyService interface {
+	// Activate generates and persists a new deploy key for repo using
+	// the algorithm the service was configured with.
+	Activate(repo *Repo) (*Key, error)
+
+	// Rotate regenerates the deploy key for repo, replacing whatever
+	// key, if any, was previously stored for it.
+	Rotate(repo *Repo) (*Key, error)
+}
+
+type keyService struct {
+	store     KeyStore
+	algorithm KeyAlgorithm
+}
+
+// NewKeyService returns a KeyService that persists generated keys to
+// store, generating them with algorithm. The algorithm is chosen by a
+// server config flag so operators can select Ed25519 or RSA fleet-wide.
+func NewKeyService(store KeyStore, algorithm KeyAlgorithm) KeyService {
+	return &keyService{store: store, algorithm: algorithm}
+}
+
+func (s *keyService) Activate(repo *Repo) (*Key, error) {
+	key, err := NewKey(repo.ID, s.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateKey(key); err != nil {
+		return nil, err
+	}
+	Events.Publish(RepoEvent{Kind: EventRepoKeyActivated, RepoID: repo.ID, Labels: repoLabels(repo)})
+	return key, nil
+}
+
+func (s *keyService) Rotate(repo *Repo) (*Key, error) {
+	key, err := NewKey(repo.ID, s.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := s.store.KeyByRepoID(repo.ID); err == nil && existing != nil {
+		key.ID = existing.ID
+		if err := s.store.UpdateKey(key); err != nil {
+			return nil, err
+		}
+		Events.Publish(RepoEvent{Kind: EventRepoKeyRotated, RepoID: repo.ID, Labels: repoLabels(repo)})
+		return key, nil
+	}
+	if err := s.store.CreateKey(key); err != nil {
+		return nil, err
+	}
+	Events.Publish(RepoEvent{Kind: EventRepoKeyRotated, RepoID: repo.ID, Labels: repoLabels(repo)})
+	return key, nil
+}
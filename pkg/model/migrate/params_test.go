@@ -0,0 +1,59 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/bscott/drone/pkg/model"
+)
+
+// fakeCipher implements model.ParamsCipher by recording the params it is
+// asked to encrypt and returning fixed bytes, enough to exercise
+// encryptGobParams without real cryptography.
+type fakeCipher struct{ encrypted map[string]string }
+
+func (c *fakeCipher) Encrypt(params map[string]string) ([]byte, error) {
+	c.encrypted = params
+	return []byte("encrypted"), nil
+}
+
+func (c *fakeCipher) Decrypt(ciphertext []byte) (map[string]string, error) {
+	return c.encrypted, nil
+}
+
+func gobEncode(t *testing.T, params map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(params); err != nil {
+		t.Fatalf("gob encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncryptGobParams(t *testing.T) {
+	want := map[string]string{"FOO": "bar", "BAZ": "qux"}
+	blob := gobEncode(t, want)
+	cipher := &fakeCipher{}
+
+	got, err := encryptGobParams(blob, cipher)
+	if err != nil {
+		t.Fatalf("encryptGobParams returned error: %v", err)
+	}
+	if string(got) != "encrypted" {
+		t.Errorf("encryptGobParams returned %q, want %q", got, "encrypted")
+	}
+	if !reflect.DeepEqual(cipher.encrypted, want) {
+		t.Errorf("cipher.Encrypt called with %v, want %v", cipher.encrypted, want)
+	}
+}
+
+func TestEncryptGobParamsInvalidBlob(t *testing.T) {
+	_, err := encryptGobParams([]byte("not gob data"), &fakeCipher{})
+	if err == nil {
+		t.Error("encryptGobParams with a malformed blob should return an error")
+	}
+}
+
+var _ model.ParamsCipher = (*fakeCipher)(nil)
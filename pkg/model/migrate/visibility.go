@@ -0,0 +1,35 @@
+// Package migrate contains one-off schema migrations for the model
+// package's datastore tables.
+package migrate
+
+import "database/sql"
+
+// addVisibilityColumnsStatements are run in order, each as its own
+// Exec, rather than as a single multi-statement string: MySQL rejects
+// multi-statement Exec unless multiStatements=true, and Postgres/SQLite
+// driver support is similarly inconsistent. Drone supports all three.
+var addVisibilityColumnsStatements = []string{
+	`ALTER TABLE repos ADD COLUMN is_scm_private BOOLEAN NOT NULL DEFAULT false`,
+	`ALTER TABLE repos ADD COLUMN visibility TEXT NOT NULL DEFAULT ''`,
+	`UPDATE repos SET is_scm_private = private`,
+	`UPDATE repos SET visibility = 'private' WHERE private = true`,
+	`UPDATE repos SET visibility = 'public'  WHERE private = false`,
+}
+
+// AddVisibilityColumns adds the is_scm_private and visibility columns to
+// the repos table and backfills them from the existing private column,
+// so that access control can move from Repo.Private to Repo.Visibility()
+// without losing the prior state of existing rows.
+func AddVisibilityColumns(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range addVisibilityColumnsStatements {
+		if _, err := tx.Exec(stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
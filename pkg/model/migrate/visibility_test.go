@@ -0,0 +1,19 @@
+package migrate
+
+import (
+	"regexp"
+	"testing"
+)
+
+// intBooleanLiteral matches the integer-as-boolean idiom (DEFAULT 0/1,
+// = 0, = 1) that PostgreSQL rejects for a boolean column, to guard
+// against regressing the fix that replaced them with true/false.
+var intBooleanLiteral = regexp.MustCompile(`(?i)(DEFAULT|=)\s*[01]\b`)
+
+func TestAddVisibilityColumnsStatementsPortable(t *testing.T) {
+	for i, stmt := range addVisibilityColumnsStatements {
+		if intBooleanLiteral.MatchString(stmt) {
+			t.Errorf("statement %d uses an integer boolean literal, which PostgreSQL rejects: %q", i, stmt)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fakeParamsCipher implements ParamsCipher with plain JSON, enough to
+// exercise the encrypt/decrypt round trip without any real cryptography.
+type fakeParamsCipher struct{}
+
+func (fakeParamsCipher) Encrypt(params map[string]string) ([]byte, error) {
+	return json.Marshal(params)
+}
+
+func (fakeParamsCipher) Decrypt(ciphertext []byte) (map[string]string, error) {
+	var params map[string]string
+	if err := json.Unmarshal(ciphertext, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// withParamsCipher installs c for the duration of the test and restores
+// whatever was previously installed afterward, since paramsCipher is a
+// package-level singleton shared across tests.
+func withParamsCipher(t *testing.T, c ParamsCipher) {
+	t.Helper()
+	prev := paramsCipher
+	paramsCipher = c
+	t.Cleanup(func() { paramsCipher = prev })
+}
+
+func TestRepoParamsRoundTrip(t *testing.T) {
+	withParamsCipher(t, fakeParamsCipher{})
+
+	r := &Repo{ID: 1}
+
+	if err := r.SetParam("FOO", "bar"); err != nil {
+		t.Fatalf("SetParam returned error: %v", err)
+	}
+	if len(r.EncryptedParams) == 0 {
+		t.Error("SetParam did not populate EncryptedParams")
+	}
+
+	// Force a re-decrypt from EncryptedParams to prove the round trip,
+	// not just the in-memory map.
+	r.Params = nil
+
+	got, err := r.GetParam("FOO")
+	if err != nil {
+		t.Fatalf("GetParam returned error: %v", err)
+	}
+	if got != "bar" {
+		t.Errorf("GetParam(%q) = %q, want %q", "FOO", got, "bar")
+	}
+
+	if err := r.DeleteParam("FOO"); err != nil {
+		t.Fatalf("DeleteParam returned error: %v", err)
+	}
+	r.Params = nil
+	if got, err := r.GetParam("FOO"); err != nil || got != "" {
+		t.Errorf("GetParam(%q) after delete = (%q, %v), want (\"\", nil)", "FOO", got, err)
+	}
+}
+
+func TestRepoGetParamEmptyEncryptedParams(t *testing.T) {
+	withParamsCipher(t, nil)
+
+	r := &Repo{ID: 1}
+	got, err := r.GetParam("FOO")
+	if err != nil {
+		t.Fatalf("GetParam on a repo with no params returned error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("GetParam(%q) = %q, want \"\"", "FOO", got)
+	}
+}
+
+func TestRepoParamsErrParamsCipherUnset(t *testing.T) {
+	withParamsCipher(t, nil)
+
+	r := &Repo{ID: 1, EncryptedParams: []byte(`{"FOO":"bar"}`)}
+	if _, err := r.GetParam("FOO"); err != ErrParamsCipherUnset {
+		t.Errorf("GetParam with no cipher installed returned %v, want ErrParamsCipherUnset", err)
+	}
+
+	r2 := &Repo{ID: 2, Params: map[string]string{}}
+	if err := r2.SetParam("FOO", "bar"); err != ErrParamsCipherUnset {
+		t.Errorf("SetParam with no cipher installed returned %v, want ErrParamsCipherUnset", err)
+	}
+}
+
+func TestRepoParamsMultipleKeysRoundTrip(t *testing.T) {
+	withParamsCipher(t, fakeParamsCipher{})
+
+	r := &Repo{ID: 1}
+	r.SetParam("A", "1")
+	r.SetParam("B", "2")
+
+	r.Params = nil
+	if err := r.decryptParams(); err != nil {
+		t.Fatalf("decryptParams returned error: %v", err)
+	}
+	want := map[string]string{"A": "1", "B": "2"}
+	if !reflect.DeepEqual(r.Params, want) {
+		t.Errorf("Params = %v, want %v", r.Params, want)
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import "fmt"
+
+// RepoProvider describes a source code hosting service capable of
+// resolving clone URLs for repositories it hosts. Implementations are
+// registered with RegisterProvider so that the New<Provider>Repo helpers
+// can resolve clone URLs without a hard-coded switch on host (NewRepo
+// takes its URL as an argument and does not consult the registry),
+// allowing downstream code to plug in self-hosted instances (for example a
+// custom Gitea URL base) without patching this package.
+type RepoProvider interface {
+	// CloneURL returns the clone URL for the named owner/repo pair. When
+	// private is true the SSH form is returned, otherwise the public
+	// HTTPS (or git) form is returned.
+	CloneURL(owner, name string, private bool) string
+}
+
+// RepoProviderFunc adapts a function to the RepoProvider interface.
+type RepoProviderFunc func(owner, name string, private bool) string
+
+// CloneURL calls f(owner, name, private).
+func (f RepoProviderFunc) CloneURL(owner, name string, private bool) string {
+	return f(owner, name, private)
+}
+
+// providers maps a host name, such as github.com, to the RepoProvider
+// responsible for building clone URLs for repositories on that host.
+var providers = map[string]RepoProvider{}
+
+// RegisterProvider registers the RepoProvider responsible for building
+// clone URLs for repositories hosted at host. Registering a provider for
+// an existing host replaces it, which allows self-hosted instances (for
+// example a private Gitea or Bitbucket Server install) to override the
+// default public host or register an entirely new one.
+func RegisterProvider(host string, p RepoProvider) {
+	providers[host] = p
+}
+
+// BuildCloneURL returns the clone URL for the repository identified by
+// host, owner and name. It returns an error if no RepoProvider has been
+// registered for host.
+func BuildCloneURL(host, owner, name string, private bool) (string, error) {
+	p, ok := providers[host]
+	if !ok {
+		return "", fmt.Errorf("model: no repo provider registered for host %s", host)
+	}
+	return p.CloneURL(owner, name, private), nil
+}
+
+// scmDefaultBranches maps an SCM kind to the branch name new repositories
+// of that kind should use by default.
+var scmDefaultBranches = map[SCMKind]string{
+	ScmGit: DefaultBranchGit,
+	ScmHg:  DefaultBranchHg,
+	ScmSvn: DefaultBranchSvn,
+}
+
+// DefaultBranch returns the default branch name for the given SCM kind,
+// falling back to DefaultBranchGit when scm is unrecognized. It backs the
+// Repo.DefaultBranch method; NewRepo does not call it.
+func DefaultBranch(scm SCMKind) string {
+	if branch, ok := scmDefaultBranches[scm]; ok {
+		return branch
+	}
+	return DefaultBranchGit
+}
+
+func init() {
+	RegisterProvider(HostGithub, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(githubRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(githubRepoPattern, owner, name)
+	}))
+
+	RegisterProvider(HostBitbucket, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(bitbucketRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(bitbucketRepoPattern, owner, name)
+	}))
+
+	RegisterProvider(HostGitlab, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(gitlabRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(gitlabRepoPattern, owner, name)
+	}))
+
+	RegisterProvider(HostGitea, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(giteaRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(giteaRepoPattern, owner, name)
+	}))
+
+	RegisterProvider(HostGogs, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(gogsRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(gogsRepoPattern, owner, name)
+	}))
+
+	RegisterProvider(HostBitbucketServer, RepoProviderFunc(func(owner, name string, private bool) string {
+		if private {
+			return fmt.Sprintf(bitbucketServerRepoPatternPrivate, owner, name)
+		}
+		return fmt.Sprintf(bitbucketServerRepoPattern, owner, name)
+	}))
+}
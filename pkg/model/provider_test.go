@@ -0,0 +1,75 @@
+package model
+
+import "testing"
+
+func TestBuildCloneURL(t *testing.T) {
+	tests := []struct {
+		host    string
+		owner   string
+		name    string
+		private bool
+		want    string
+	}{
+		{HostGithub, "octocat", "hello-world", false, "git://github.com/octocat/hello-world.git"},
+		{HostGithub, "octocat", "hello-world", true, "git@github.com:octocat/hello-world.git"},
+		{HostBitbucket, "octocat", "hello-world", false, "https://bitbucket.org/octocat/hello-world.git"},
+		{HostBitbucket, "octocat", "hello-world", true, "git@bitbucket.org:octocat/hello-world.git"},
+		{HostGitlab, "octocat", "hello-world", false, "https://gitlab.com/octocat/hello-world.git"},
+		{HostGitlab, "octocat", "hello-world", true, "git@gitlab.com:octocat/hello-world.git"},
+		{HostGitea, "octocat", "hello-world", false, "https://gitea.com/octocat/hello-world.git"},
+		{HostGogs, "octocat", "hello-world", false, "https://gogs.io/octocat/hello-world.git"},
+		{HostBitbucketServer, "octocat", "hello-world", false, "https://bitbucket-server/scm/octocat/hello-world.git"},
+	}
+
+	for _, test := range tests {
+		got, err := BuildCloneURL(test.host, test.owner, test.name, test.private)
+		if err != nil {
+			t.Errorf("BuildCloneURL(%q, %q, %q, %v) returned error: %v", test.host, test.owner, test.name, test.private, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("BuildCloneURL(%q, %q, %q, %v) = %q, want %q", test.host, test.owner, test.name, test.private, got, test.want)
+		}
+	}
+}
+
+func TestBuildCloneURLUnregisteredHost(t *testing.T) {
+	if _, err := BuildCloneURL("example.com", "octocat", "hello-world", false); err == nil {
+		t.Fatal("BuildCloneURL with an unregistered host should return an error")
+	}
+}
+
+func TestRegisterProviderOverridesExisting(t *testing.T) {
+	const host = "git.example.com"
+	RegisterProvider(host, RepoProviderFunc(func(owner, name string, private bool) string {
+		return "ssh://git@" + host + "/" + owner + "/" + name
+	}))
+	defer delete(providers, host)
+
+	got, err := BuildCloneURL(host, "acme", "widgets", true)
+	if err != nil {
+		t.Fatalf("BuildCloneURL returned error: %v", err)
+	}
+	want := "ssh://git@git.example.com/acme/widgets"
+	if got != want {
+		t.Errorf("BuildCloneURL(%q, ...) = %q, want %q", host, got, want)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	tests := []struct {
+		scm  SCMKind
+		want string
+	}{
+		{ScmGit, DefaultBranchGit},
+		{ScmHg, DefaultBranchHg},
+		{ScmSvn, DefaultBranchSvn},
+		{SCMKind("unknown"), DefaultBranchGit},
+	}
+
+	for _, test := range tests {
+		if got := DefaultBranch(test.scm); got != test.want {
+			t.Errorf("DefaultBranch(%q) = %q, want %q", test.scm, got, test.want)
+		}
+	}
+}
@@ -6,16 +6,20 @@ import (
 )
 
 const (
-	ScmGit = "git"
-	ScmHg  = "hg"
-	ScmSvn = "svn"
+	ScmGit SCMKind = "git"
+	ScmHg  SCMKind = "hg"
+	ScmSvn SCMKind = "svn"
 )
 
 const (
-	HostGithub    = "github.com"
-	HostBitbucket = "bitbucket.org"
-	HostGoogle    = "code.google.com"
-	HostCustom    = "custom"
+	HostGithub          = "github.com"
+	HostBitbucket       = "bitbucket.org"
+	HostGoogle          = "code.google.com"
+	HostGitlab          = "gitlab.com"
+	HostGitea           = "gitea.com"
+	HostGogs            = "gogs.io"
+	HostBitbucketServer = "bitbucket-server"
+	HostCustom          = "custom"
 )
 
 const (
@@ -29,6 +33,15 @@ const (
 	githubRepoPatternPrivate    = "git@github.com:%s/%s.git"
 	bitbucketRepoPattern        = "https://bitbucket.org/%s/%s.git"
 	bitbucketRepoPatternPrivate = "git@bitbucket.org:%s/%s.git"
+
+	gitlabRepoPattern                 = "https://gitlab.com/%s/%s.git"
+	gitlabRepoPatternPrivate          = "git@gitlab.com:%s/%s.git"
+	giteaRepoPattern                  = "https://gitea.com/%s/%s.git"
+	giteaRepoPatternPrivate           = "git@gitea.com:%s/%s.git"
+	gogsRepoPattern                   = "https://gogs.io/%s/%s.git"
+	gogsRepoPatternPrivate            = "git@gogs.io:%s/%s.git"
+	bitbucketServerRepoPattern        = "https://bitbucket-server/scm/%s/%s.git"
+	bitbucketServerRepoPatternPrivate = "git@bitbucket-server:%s/%s.git"
 )
 
 type Repo struct {
@@ -52,8 +65,25 @@ type Repo struct {
 
 	// A value of True indicates the repository is closed source,
 	// while a value of False indicates the project is open source.
+	//
+	// Deprecated: Private conflated "private on the upstream SCM" with
+	// "visible on this CI server". Use IsSCMPrivate and Visibility
+	// instead; this field is retained only so existing rows can be
+	// migrated and is no longer consulted for access control.
 	Private bool `meddler:"private" json:"private"`
 
+	// IsSCMPrivate reflects whether the repository is private on the
+	// upstream host. It does not by itself control who may see the
+	// repository on this CI server; see Visibility for that.
+	IsSCMPrivate bool `meddler:"is_scm_private" json:"is_scm_private"`
+
+	// Visibility controls who may see the repository on this CI server:
+	// public (anyone), private (members only) or internal (any logged-in
+	// user). When empty, callers should use the Repo.Visibility() method
+	// rather than reading this field directly, since it falls back to a
+	// value derived from IsSCMPrivate.
+	RepoVisibility RepoVisibility `meddler:"visibility" json:"visibility"`
+
 	// A value of True indicates the repository is disabled and
 	// no builds should be executed
 	Disabled bool `meddler:"disabled" json:"disabled"`
@@ -62,9 +92,16 @@ type Repo struct {
 	// for the repository and no builds will be executed
 	DisabledPullRequest bool `meddler:"disabled_pr" json:"disabled_pr"`
 
+	// ConfigPath is a custom path to the build config file, relative to
+	// the repository root, such as ci/drone.yml. An empty value means
+	// the default, DefaultConfigPath (.drone.yml), is used. ConfigPath
+	// only takes effect once DefaultConfigPath is absent from the repo:
+	// see ResolveConfig.
+	ConfigPath string `meddler:"config" json:"config_path"`
+
 	// indicates the type of repository, such as
-	// Git, Mercurial, Subversion or Bazaar.
-	SCM string `meddler:"scm" json:"scm"`
+	// Git, Mercurial or Subversion.
+	SCM SCMKind `meddler:"scm" json:"scm"`
 
 	// the repository URL, for example:
 	// git://github.com/bradrydzewski/go.stripe.git
@@ -75,14 +112,17 @@ type Repo struct {
 	Username string `meddler:"username" json:"username"`
 	Password string `meddler:"password" json:"password"`
 
-	// RSA key pair that will injected into the virtual machine
-	// .ssh/id_rsa and .ssh/id_rsa.pub files.
-	PublicKey  string `meddler:"public_key"  json:"public_key"`
-	PrivateKey string `meddler:"private_key" json:"public_key"`
+	// EncryptedParams is the JWE-encrypted form of Params, stored at
+	// rest in place of the historical plaintext gob blob. Use
+	// GetParam/SetParam/DeleteParam rather than reading or writing it
+	// directly.
+	EncryptedParams []byte `meddler:"params" json:"-"`
 
-	// Parameters stored external to the repository in YAML
-	// format, injected into the Build YAML at runtime.
-	Params map[string]string `meddler:"params,gob" json:"-"`
+	// Params holds environment variables stored external to the
+	// repository, injected into the Build YAML at runtime. It is
+	// decrypted from EncryptedParams lazily, on first access through
+	// GetParam/SetParam/DeleteParam, and is never persisted directly.
+	Params map[string]string `meddler:"-" json:"-"`
 
 	// the amount of time, in seconds the build will execute
 	// before exceeding its timelimit and being killed.
@@ -103,7 +143,17 @@ type Repo struct {
 }
 
 // Creates a new repository
-func NewRepo(host, owner, name, scm, url string) (*Repo, error) {
+func NewRepo(host, owner, name string, scm SCMKind, url string) (*Repo, error) {
+	return newRepo(host, owner, name, scm, url, false)
+}
+
+// newRepo creates a new repository, deriving IsSCMPrivate and Visibility
+// from isSCMPrivate. Deploy key generation is not part of repo creation;
+// it happens later, when the repo is activated, via a KeyService. No
+// EventRepoCreated is published here: the repo has no ID yet, so per-repo
+// topic subscribers could never receive it. Call PublishCreated once the
+// datastore has persisted the repo and assigned it an ID.
+func newRepo(host, owner, name string, scm SCMKind, url string, isSCMPrivate bool) (*Repo, error) {
 	repo := Repo{}
 	repo.URL = url
 	repo.SCM = scm
@@ -111,49 +161,109 @@ func NewRepo(host, owner, name, scm, url string) (*Repo, error) {
 	repo.Owner = owner
 	repo.Name = name
 	repo.Slug = fmt.Sprintf("%s/%s/%s", host, owner, name)
-	key, err := generatePrivateKey()
-	if err != nil {
-		return nil, err
-	}
-
-	repo.PublicKey = marshalPublicKey(&key.PublicKey)
-	repo.PrivateKey = marshalPrivateKey(key)
+	repo.Private = isSCMPrivate
+	repo.IsSCMPrivate = isSCMPrivate
+	repo.RepoVisibility = DefaultVisibility(isSCMPrivate)
 	return &repo, nil
 }
 
+// PublishCreated publishes an EventRepoCreated event for the repo. It is
+// the hook point the datastore's repo creation should call once the row
+// has been persisted and assigned an ID, so that per-repo topic
+// subscribers, not just the firehose, receive the event with a non-zero
+// repo_id.
+func (r *Repo) PublishCreated() {
+	Events.Publish(RepoEvent{Kind: EventRepoCreated, RepoID: r.ID, Labels: repoLabels(r)})
+}
+
+// Enable marks the repo as active so its builds are executed, and
+// publishes an EventRepoEnabled event. The caller must still persist
+// the repo for the change to be durable.
+func (r *Repo) Enable() {
+	r.Disabled = false
+	Events.Publish(RepoEvent{Kind: EventRepoEnabled, RepoID: r.ID, Labels: repoLabels(r)})
+}
+
+// Disable marks the repo as inactive so no further builds are executed,
+// and publishes an EventRepoDisabled event. The caller must still
+// persist the repo for the change to be durable.
+func (r *Repo) Disable() {
+	r.Disabled = true
+	Events.Publish(RepoEvent{Kind: EventRepoDisabled, RepoID: r.ID, Labels: repoLabels(r)})
+}
+
+// Delete publishes an EventRepoDeleted event. It is the hook point the
+// datastore's repo deletion should call after removing the row, so that
+// subscribers (webhook dispatcher, metrics, audit log) learn of the
+// deletion without the datastore calling each of them directly.
+func (r *Repo) Delete() {
+	Events.Publish(RepoEvent{Kind: EventRepoDeleted, RepoID: r.ID, Labels: repoLabels(r)})
+}
+
+// Visibility returns who may see the repository on this CI server. If
+// RepoVisibility has not been explicitly set it falls back to a value
+// derived from IsSCMPrivate.
+func (r *Repo) Visibility() RepoVisibility {
+	if r.RepoVisibility != "" {
+		return r.RepoVisibility
+	}
+	return DefaultVisibility(r.IsSCMPrivate)
+}
+
 // Creates a new GitHub repository
 func NewGitHubRepo(owner, name string, private bool) (*Repo, error) {
-	var url string
-	switch private {
-	case false:
-		url = fmt.Sprintf(githubRepoPattern, owner, name)
-	case true:
-		url = fmt.Sprintf(githubRepoPatternPrivate, owner, name)
+	url, err := BuildCloneURL(HostGithub, owner, name, private)
+	if err != nil {
+		return nil, err
 	}
-	return NewRepo(HostGithub, owner, name, ScmGit, url)
+	return newRepo(HostGithub, owner, name, ScmGit, url, private)
 }
 
 // Creates a new Bitbucket repository
 func NewBitbucketRepo(owner, name string, private bool) (*Repo, error) {
-	var url string
-	switch private {
-	case false:
-		url = fmt.Sprintf(bitbucketRepoPattern, owner, name)
-	case true:
-		url = fmt.Sprintf(bitbucketRepoPatternPrivate, owner, name)
+	url, err := BuildCloneURL(HostBitbucket, owner, name, private)
+	if err != nil {
+		return nil, err
 	}
-	return NewRepo(HostGithub, owner, name, ScmGit, url)
+	return newRepo(HostBitbucket, owner, name, ScmGit, url, private)
 }
 
-func (r *Repo) DefaultBranch() string {
-	switch r.SCM {
-	case ScmGit:
-		return DefaultBranchGit
-	case ScmHg:
-		return DefaultBranchHg
-	case ScmSvn:
-		return DefaultBranchSvn
-	default:
-		return DefaultBranchGit
+// Creates a new GitLab repository
+func NewGitlabRepo(owner, name string, private bool) (*Repo, error) {
+	url, err := BuildCloneURL(HostGitlab, owner, name, private)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(HostGitlab, owner, name, ScmGit, url, private)
+}
+
+// Creates a new Gitea repository
+func NewGiteaRepo(owner, name string, private bool) (*Repo, error) {
+	url, err := BuildCloneURL(HostGitea, owner, name, private)
+	if err != nil {
+		return nil, err
 	}
+	return newRepo(HostGitea, owner, name, ScmGit, url, private)
+}
+
+// Creates a new Gogs repository
+func NewGogsRepo(owner, name string, private bool) (*Repo, error) {
+	url, err := BuildCloneURL(HostGogs, owner, name, private)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(HostGogs, owner, name, ScmGit, url, private)
+}
+
+// Creates a new Bitbucket Server repository
+func NewBitbucketServerRepo(owner, name string, private bool) (*Repo, error) {
+	url, err := BuildCloneURL(HostBitbucketServer, owner, name, private)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(HostBitbucketServer, owner, name, ScmGit, url, private)
+}
+
+func (r *Repo) DefaultBranch() string {
+	return DefaultBranch(r.SCM)
 }
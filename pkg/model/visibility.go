@@ -0,0 +1,71 @@
+package model
+
+import "fmt"
+
+// SCMKind identifies the type of version control system backing a
+// repository, such as Git, Mercurial or Subversion.
+type SCMKind string
+
+// RepoVisibility controls who may see a repository on this CI server.
+// It is distinct from IsSCMPrivate, which only reflects whether the
+// repository is private on the upstream host: a repository can be
+// public upstream yet restricted to logged-in users here, or vice versa.
+type RepoVisibility string
+
+const (
+	VisibilityPublic   RepoVisibility = "public"
+	VisibilityPrivate  RepoVisibility = "private"
+	VisibilityInternal RepoVisibility = "internal"
+)
+
+// DefaultVisibility derives the RepoVisibility a repository should use
+// when none has been explicitly chosen, based on whether the upstream
+// repository is private. It is the default used by NewRepo; the migration
+// that backfills Visibility from the legacy Private column applies the
+// same mapping in SQL rather than calling it.
+func DefaultVisibility(isSCMPrivate bool) RepoVisibility {
+	if isSCMPrivate {
+		return VisibilityPrivate
+	}
+	return VisibilityPublic
+}
+
+// IsValid reports whether v is one of the known RepoVisibility values.
+func (v RepoVisibility) IsValid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityPrivate, VisibilityInternal:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetVisibility changes who may see the repo on this CI server and
+// publishes an EventRepoVisibilityChanged event. The caller must still
+// persist the repo for the change to be durable. It returns an error if
+// v is not a recognized RepoVisibility.
+func (r *Repo) SetVisibility(v RepoVisibility) error {
+	if !v.IsValid() {
+		return fmt.Errorf("model: invalid repo visibility %q", v)
+	}
+	r.RepoVisibility = v
+	Events.Publish(RepoEvent{Kind: EventRepoVisibilityChanged, RepoID: r.ID, Labels: repoLabels(r)})
+	return nil
+}
+
+// CanView reports whether a caller may see the repo on this CI server,
+// given whether they are authenticated (logged in to this server) and
+// whether they are a member of the repo (owner, team member, or
+// collaborator on the upstream host). Auth middleware and the API layer
+// should consult this instead of reading Repo.Private directly, which
+// cannot express the "internal" (any logged-in user) case.
+func (r *Repo) CanView(authenticated, isMember bool) bool {
+	switch r.Visibility() {
+	case VisibilityPublic:
+		return true
+	case VisibilityInternal:
+		return authenticated || isMember
+	default: // VisibilityPrivate
+		return isMember
+	}
+}
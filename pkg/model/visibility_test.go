@@ -0,0 +1,80 @@
+package model
+
+import "testing"
+
+func TestDefaultVisibility(t *testing.T) {
+	tests := []struct {
+		isSCMPrivate bool
+		want         RepoVisibility
+	}{
+		{isSCMPrivate: false, want: VisibilityPublic},
+		{isSCMPrivate: true, want: VisibilityPrivate},
+	}
+
+	for _, test := range tests {
+		if got := DefaultVisibility(test.isSCMPrivate); got != test.want {
+			t.Errorf("DefaultVisibility(%v) = %q, want %q", test.isSCMPrivate, got, test.want)
+		}
+	}
+}
+
+func TestRepoVisibilityIsValid(t *testing.T) {
+	tests := []struct {
+		v    RepoVisibility
+		want bool
+	}{
+		{VisibilityPublic, true},
+		{VisibilityPrivate, true},
+		{VisibilityInternal, true},
+		{RepoVisibility(""), false},
+		{RepoVisibility("bogus"), false},
+	}
+
+	for _, test := range tests {
+		if got := test.v.IsValid(); got != test.want {
+			t.Errorf("RepoVisibility(%q).IsValid() = %v, want %v", test.v, got, test.want)
+		}
+	}
+}
+
+func TestRepoSetVisibility(t *testing.T) {
+	r := &Repo{}
+	if err := r.SetVisibility(VisibilityInternal); err != nil {
+		t.Fatalf("SetVisibility(VisibilityInternal) returned error: %v", err)
+	}
+	if r.Visibility() != VisibilityInternal {
+		t.Errorf("Visibility() = %q, want %q", r.Visibility(), VisibilityInternal)
+	}
+
+	if err := r.SetVisibility(RepoVisibility("bogus")); err == nil {
+		t.Error("SetVisibility with an invalid value should return an error")
+	}
+	if r.Visibility() != VisibilityInternal {
+		t.Errorf("Visibility() changed after a rejected SetVisibility call: got %q", r.Visibility())
+	}
+}
+
+func TestRepoCanView(t *testing.T) {
+	tests := []struct {
+		visibility    RepoVisibility
+		authenticated bool
+		isMember      bool
+		want          bool
+	}{
+		{VisibilityPublic, false, false, true},
+		{VisibilityPublic, true, false, true},
+		{VisibilityInternal, false, false, false},
+		{VisibilityInternal, true, false, true},
+		{VisibilityInternal, false, true, true},
+		{VisibilityPrivate, true, false, false},
+		{VisibilityPrivate, false, true, true},
+	}
+
+	for _, test := range tests {
+		r := &Repo{RepoVisibility: test.visibility}
+		if got := r.CanView(test.authenticated, test.isMember); got != test.want {
+			t.Errorf("CanView(authenticated=%v, isMember=%v) with visibility %q = %v, want %v",
+				test.authenticated, test.isMember, test.visibility, got, test.want)
+		}
+	}
+}